@@ -0,0 +1,317 @@
+// Package jwks fetches and caches JSON Web Key Sets so that incoming JWTs
+// (and JWS structures more generally) can have their signatures verified.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Key is a single parsed entry from a JWKS document, holding whichever of
+// RSA, EC or RSA public key material the `kty` called for.
+type Key struct {
+	Kid string
+	Alg string
+	Kty string
+	RSA *rsa.PublicKey
+	EC  *ecdsa.PublicKey
+}
+
+// rawJWK mirrors the JSON shape of a single entry in a JWKS `keys` array.
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type rawJWKS struct {
+	Keys []rawJWK `json:"keys"`
+}
+
+// Store fetches a JWKS document over HTTP and caches its keys by `kid`,
+// optionally refreshing itself in the background on a timer.
+type Store struct {
+	url    string
+	client *http.Client
+	keys   sync.Map // kid -> *Key
+}
+
+// NewStore creates a Store that fetches keys from url. Call Refresh at
+// least once (or StartBackgroundRefresh) before looking up any keys.
+func NewStore(url string) *Store {
+	return &Store{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Lookup returns the cached key for kid, if any.
+func (s *Store) Lookup(kid string) (*Key, bool) {
+	v, ok := s.keys.Load(kid)
+	if !ok {
+		return nil, false
+	}
+	return v.(*Key), true
+}
+
+// Refresh fetches and parses the JWKS document and replaces the entire
+// cached key set with it, so a `kid` that was rotated or revoked out of
+// the document stops being trusted.
+func (s *Store) Refresh() error {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return fmt.Errorf("jwks: fetch %s: %w", s.url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks: fetch %s: unexpected status %d", s.url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("jwks: read %s: %w", s.url, err)
+	}
+
+	var doc rawJWKS
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("jwks: parse %s: %w", s.url, err)
+	}
+
+	seen := make(map[string]struct{}, len(doc.Keys))
+	for _, raw := range doc.Keys {
+		key, err := parseJWK(raw)
+		if err != nil {
+			continue
+		}
+		seen[key.Kid] = struct{}{}
+		s.keys.Store(key.Kid, key)
+	}
+
+	s.keys.Range(func(k, _ any) bool {
+		if _, ok := seen[k.(string)]; !ok {
+			s.keys.Delete(k)
+		}
+		return true
+	})
+
+	return nil
+}
+
+// StartBackgroundRefresh performs an initial Refresh and then repeats it
+// every interval until stop is closed.
+func (s *Store) StartBackgroundRefresh(interval time.Duration, stop <-chan struct{}) {
+	if err := s.Refresh(); err != nil {
+		// Logged by the caller; a failed initial fetch is not fatal, the
+		// ticker below will keep retrying.
+		_ = err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_ = s.Refresh()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func parseJWK(raw rawJWK) (*Key, error) {
+	switch raw.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(raw.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: decode n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(raw.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: decode e: %w", err)
+		}
+		return &Key{
+			Kid: raw.Kid,
+			Alg: raw.Alg,
+			Kty: raw.Kty,
+			RSA: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			},
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch raw.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("jwks: unsupported curve %q", raw.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(raw.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: decode x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(raw.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: decode y: %w", err)
+		}
+		return &Key{
+			Kid: raw.Kid,
+			Alg: raw.Alg,
+			Kty: raw.Kty,
+			EC: &ecdsa.PublicKey{
+				Curve: curve,
+				X:     new(big.Int).SetBytes(x),
+				Y:     new(big.Int).SetBytes(y),
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("jwks: unsupported kty %q", raw.Kty)
+	}
+}
+
+// Verifier checks JWT/JWS signatures against keys served by a Store and/or
+// a static HMAC secret.
+type Verifier struct {
+	store      *Store
+	hmacSecret []byte
+}
+
+// NewVerifier builds a Verifier. store may be nil if only HMAC is in use;
+// hmacSecret may be nil if only JWKS-backed keys are in use.
+func NewVerifier(store *Store, hmacSecret []byte) *Verifier {
+	return &Verifier{store: store, hmacSecret: hmacSecret}
+}
+
+// ErrAlgNone is returned when a token claims `alg: none`, which is never
+// accepted regardless of configuration.
+var ErrAlgNone = errors.New("jwks: alg \"none\" is not permitted")
+
+// hashForAlg returns the crypto.Hash used by alg's signature scheme.
+func hashForAlg(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "ES256", "HS256":
+		return crypto.SHA256, nil
+	case "RS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("jwks: unsupported alg %q", alg)
+	}
+}
+
+func sum(h crypto.Hash, data []byte) []byte {
+	switch h {
+	case crypto.SHA384:
+		sum := sha512.Sum384(data)
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512(data)
+		return sum[:]
+	default:
+		sum := sha256.Sum256(data)
+		return sum[:]
+	}
+}
+
+// Verify checks sig over signingInput for the given alg/kid. A nil error
+// means the signature is valid.
+func (v *Verifier) Verify(alg, kid string, signingInput, sig []byte) error {
+	if alg == "" || alg == "none" {
+		return ErrAlgNone
+	}
+
+	hash, err := hashForAlg(alg)
+	if err != nil {
+		return err
+	}
+	hashed := sum(hash, signingInput)
+
+	switch {
+	case alg == "HS256":
+		if len(v.hmacSecret) == 0 {
+			return errors.New("jwks: no HMAC secret configured")
+		}
+		mac := hmac.New(sha256.New, v.hmacSecret)
+		mac.Write(signingInput)
+		expected := mac.Sum(nil)
+		if !hmac.Equal(expected, sig) {
+			return errors.New("jwks: HMAC signature mismatch")
+		}
+		return nil
+
+	case alg == "RS256" || alg == "RS384" || alg == "RS512":
+		key, err := v.lookup(kid)
+		if err != nil {
+			return err
+		}
+		if key.RSA == nil {
+			return fmt.Errorf("jwks: key %q is not an RSA key", kid)
+		}
+		if err := rsa.VerifyPKCS1v15(key.RSA, hash, hashed, sig); err != nil {
+			return fmt.Errorf("jwks: RSA signature verification failed: %w", err)
+		}
+		return nil
+
+	case alg == "ES256" || alg == "ES384":
+		key, err := v.lookup(kid)
+		if err != nil {
+			return err
+		}
+		if key.EC == nil {
+			return fmt.Errorf("jwks: key %q is not an EC key", kid)
+		}
+		if len(sig)%2 != 0 {
+			return errors.New("jwks: malformed ECDSA signature")
+		}
+		half := len(sig) / 2
+		r := new(big.Int).SetBytes(sig[:half])
+		s := new(big.Int).SetBytes(sig[half:])
+		if !ecdsa.Verify(key.EC, hashed, r, s) {
+			return errors.New("jwks: ECDSA signature verification failed")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("jwks: unsupported alg %q", alg)
+	}
+}
+
+func (v *Verifier) lookup(kid string) (*Key, error) {
+	if v.store == nil {
+		return nil, errors.New("jwks: no JWKS store configured")
+	}
+	if kid == "" {
+		return nil, errors.New("jwks: token has no kid")
+	}
+	key, ok := v.store.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("jwks: unknown kid %q", kid)
+	}
+	return key, nil
+}
@@ -0,0 +1,186 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifier_HMAC(t *testing.T) {
+	v := NewVerifier(nil, []byte("super-secret"))
+
+	signingInput := []byte("header.payload")
+	sig := hmacSign(signingInput, []byte("super-secret"))
+
+	if err := v.Verify("HS256", "", signingInput, sig); err != nil {
+		t.Errorf("expected valid HMAC signature, got error: %v", err)
+	}
+
+	if err := v.Verify("HS256", "", signingInput, []byte("not-the-signature")); err == nil {
+		t.Error("expected error for mismatched HMAC signature")
+	}
+}
+
+func TestVerifier_HMAC_NoSecretConfigured(t *testing.T) {
+	v := NewVerifier(nil, nil)
+
+	if err := v.Verify("HS256", "", []byte("x"), []byte("y")); err == nil {
+		t.Error("expected error when no HMAC secret is configured")
+	}
+}
+
+func TestVerifier_RejectsAlgNone(t *testing.T) {
+	v := NewVerifier(nil, []byte("secret"))
+
+	if err := v.Verify("none", "", []byte("x"), []byte("y")); err != ErrAlgNone {
+		t.Errorf("expected ErrAlgNone, got %v", err)
+	}
+}
+
+func TestVerifier_RSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	store := newStoreFromServer(t, jwksDocForRSA("rsa-key", &key.PublicKey))
+
+	v := NewVerifier(store, nil)
+
+	signingInput := []byte("header.payload")
+	hashed := sha256.Sum256(signingInput)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	if err := v.Verify("RS256", "rsa-key", signingInput, sig); err != nil {
+		t.Errorf("expected valid RSA signature, got error: %v", err)
+	}
+
+	if err := v.Verify("RS256", "unknown-kid", signingInput, sig); err == nil {
+		t.Error("expected error for unknown kid")
+	}
+}
+
+func TestVerifier_ECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+
+	store := newStoreFromServer(t, jwksDocForEC("ec-key", &key.PublicKey))
+
+	v := NewVerifier(store, nil)
+
+	signingInput := []byte("header.payload")
+	hashed := sha256.Sum256(signingInput)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hashed[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	sig := append(leftPad(r.Bytes(), 32), leftPad(s.Bytes(), 32)...)
+
+	if err := v.Verify("ES256", "ec-key", signingInput, sig); err != nil {
+		t.Errorf("expected valid ECDSA signature, got error: %v", err)
+	}
+}
+
+func TestStore_Refresh_DropsRotatedKeys(t *testing.T) {
+	keyA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	keyB, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	var doc rawJWKS
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(doc)
+	}))
+	t.Cleanup(server.Close)
+
+	doc = jwksDocForRSA("key-a", &keyA.PublicKey)
+	store := NewStore(server.URL)
+	if err := store.Refresh(); err != nil {
+		t.Fatalf("failed to refresh store: %v", err)
+	}
+	if _, ok := store.Lookup("key-a"); !ok {
+		t.Fatal("expected key-a to be cached after first refresh")
+	}
+
+	doc = jwksDocForRSA("key-b", &keyB.PublicKey)
+	if err := store.Refresh(); err != nil {
+		t.Fatalf("failed to refresh store: %v", err)
+	}
+
+	if _, ok := store.Lookup("key-a"); ok {
+		t.Error("expected key-a to be dropped once it's no longer in the JWKS document")
+	}
+	if _, ok := store.Lookup("key-b"); !ok {
+		t.Error("expected key-b to be cached after second refresh")
+	}
+}
+
+func hmacSign(signingInput, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(signingInput)
+	return mac.Sum(nil)
+}
+
+func newStoreFromServer(t *testing.T, doc rawJWKS) *Store {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(doc)
+	}))
+	t.Cleanup(server.Close)
+
+	store := NewStore(server.URL)
+	if err := store.Refresh(); err != nil {
+		t.Fatalf("failed to refresh store: %v", err)
+	}
+	return store
+}
+
+func jwksDocForRSA(kid string, pub *rsa.PublicKey) rawJWKS {
+	return rawJWKS{Keys: []rawJWK{{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}
+}
+
+func jwksDocForEC(kid string, pub *ecdsa.PublicKey) rawJWKS {
+	return rawJWKS{Keys: []rawJWK{{
+		Kty: "EC",
+		Kid: kid,
+		Alg: "ES256",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(leftPad(pub.X.Bytes(), 32)),
+		Y:   base64.RawURLEncoding.EncodeToString(leftPad(pub.Y.Bytes(), 32)),
+	}}}
+}
+
+func leftPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
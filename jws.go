@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strings"
+)
+
+// JWSInfo is a decoded JSON Web Signature body, as sent by ACME/JOSE
+// clients, alongside the raw `body` field so both views are available.
+type JWSInfo struct {
+	Protected any    `json:"protected,omitempty"`
+	Payload   any    `json:"payload"`
+	Algorithm string `json:"algorithm,omitempty"`
+	KeyID     string `json:"keyId,omitempty"`
+	JWK       any    `json:"jwk,omitempty"`
+	Nonce     string `json:"nonce,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Valid     bool   `json:"valid"`
+	Error     string `json:"error,omitempty"`
+}
+
+// isJOSEContentType reports whether contentType is application/jose or
+// application/jose+json, ignoring any parameters (e.g. charset).
+func isJOSEContentType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	}
+	return mediaType == "application/jose" || mediaType == "application/jose+json"
+}
+
+// decodeJWS decodes a compact (`h.p.s`) or flattened JSON
+// (`{"protected","payload","signature"}`) JWS body, sniffing which form it
+// is from the first non-whitespace byte. It never consumes body; callers
+// should keep reporting the raw body alongside the decoded result.
+func decodeJWS(body []byte) *JWSInfo {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return &JWSInfo{Error: "empty JWS body"}
+	}
+
+	var protectedB64, payloadB64, signatureB64 string
+	if trimmed[0] == '{' {
+		var flattened struct {
+			Protected string `json:"protected"`
+			Payload   string `json:"payload"`
+			Signature string `json:"signature"`
+		}
+		if err := json.Unmarshal(trimmed, &flattened); err != nil {
+			return &JWSInfo{Error: "failed to parse flattened JWS: " + err.Error()}
+		}
+		protectedB64, payloadB64, signatureB64 = flattened.Protected, flattened.Payload, flattened.Signature
+	} else {
+		parts := strings.Split(string(trimmed), ".")
+		if len(parts) != 3 {
+			return &JWSInfo{Error: "invalid JWS compact format: expected 3 parts"}
+		}
+		protectedB64, payloadB64, signatureB64 = parts[0], parts[1], parts[2]
+	}
+
+	info := &JWSInfo{}
+
+	header, err := decodeBase64JSON(protectedB64)
+	if err != nil {
+		info.Error = "failed to decode protected header: " + err.Error()
+		return info
+	}
+	info.Protected = header
+
+	if headerMap, ok := header.(map[string]any); ok {
+		info.Algorithm, _ = headerMap["alg"].(string)
+		info.KeyID, _ = headerMap["kid"].(string)
+		info.Nonce, _ = headerMap["nonce"].(string)
+		info.URL, _ = headerMap["url"].(string)
+		info.JWK = headerMap["jwk"]
+	}
+
+	switch {
+	case payloadB64 == "":
+		// POST-as-GET requests sign an empty payload.
+		info.Payload = nil
+	default:
+		if payload, err := decodeBase64JSON(payloadB64); err == nil {
+			info.Payload = payload
+		} else if raw, err := decodeBase64Segment(payloadB64); err == nil {
+			info.Payload = string(raw)
+		} else {
+			info.Error = "failed to decode payload: " + err.Error()
+			return info
+		}
+	}
+
+	sig, err := decodeBase64Segment(signatureB64)
+	if err != nil {
+		info.Error = "failed to decode signature: " + err.Error()
+		return info
+	}
+
+	if jwtVerifier != nil {
+		signingInput := protectedB64 + "." + payloadB64
+		if err := verifyJWTSignature(info.Algorithm, info.KeyID, signingInput, sig); err == nil {
+			info.Valid = true
+		}
+	}
+
+	return info
+}
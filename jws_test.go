@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func b64url(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal %v: %v", v, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func TestIsJOSEContentType(t *testing.T) {
+	cases := map[string]bool{
+		"application/jose":                     true,
+		"application/jose+json":                true,
+		"application/jose+json; charset=utf-8": true,
+		"application/json":                     false,
+		"":                                     false,
+	}
+
+	for ct, want := range cases {
+		if got := isJOSEContentType(ct); got != want {
+			t.Errorf("isJOSEContentType(%q) = %v, want %v", ct, got, want)
+		}
+	}
+}
+
+func TestDecodeJWS_Compact(t *testing.T) {
+	protected := b64url(t, map[string]any{"alg": "RS256", "kid": "key-1", "nonce": "abc", "url": "https://example.com/acme/new-order"})
+	payload := b64url(t, map[string]any{"foo": "bar"})
+	token := protected + "." + payload + ".c2lnbmF0dXJl"
+
+	info := decodeJWS([]byte(token))
+
+	if info.Error != "" {
+		t.Fatalf("unexpected error: %s", info.Error)
+	}
+	if info.Algorithm != "RS256" {
+		t.Errorf("expected alg RS256, got %s", info.Algorithm)
+	}
+	if info.KeyID != "key-1" {
+		t.Errorf("expected kid key-1, got %s", info.KeyID)
+	}
+	if info.Nonce != "abc" {
+		t.Errorf("expected nonce abc, got %s", info.Nonce)
+	}
+	if info.URL != "https://example.com/acme/new-order" {
+		t.Errorf("expected url to be surfaced, got %s", info.URL)
+	}
+
+	payloadMap, ok := info.Payload.(map[string]any)
+	if !ok {
+		t.Fatalf("expected payload to be a map, got %T", info.Payload)
+	}
+	if payloadMap["foo"] != "bar" {
+		t.Errorf("expected foo=bar, got %v", payloadMap["foo"])
+	}
+}
+
+func TestDecodeJWS_Flattened(t *testing.T) {
+	protected := b64url(t, map[string]any{"alg": "RS256", "jwk": map[string]any{"kty": "RSA"}})
+
+	body := `{"protected":"` + protected + `","payload":"","signature":"c2ln"}`
+
+	info := decodeJWS([]byte(body))
+
+	if info.Error != "" {
+		t.Fatalf("unexpected error: %s", info.Error)
+	}
+	if info.Payload != nil {
+		t.Errorf("expected nil payload for POST-as-GET, got %v", info.Payload)
+	}
+	if info.JWK == nil {
+		t.Error("expected jwk to be surfaced from the protected header")
+	}
+}
+
+func TestDecodeJWS_InvalidCompactFormat(t *testing.T) {
+	info := decodeJWS([]byte("not-a-jws"))
+
+	if info.Error == "" {
+		t.Error("expected error for invalid JWS format")
+	}
+}
+
+func TestDecodeJWS_EmptyBody(t *testing.T) {
+	info := decodeJWS([]byte("  "))
+
+	if info.Error == "" {
+		t.Error("expected error for empty JWS body")
+	}
+}
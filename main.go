@@ -3,12 +3,16 @@ package main
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/tamcore/http-https-echo/jwks"
 )
 
 // EchoResponse represents the JSON response structure
@@ -23,6 +27,7 @@ type EchoResponse struct {
 	Protocol string              `json:"protocol"`
 	OS       OSInfo              `json:"os"`
 	JWT      *JWTInfo            `json:"jwt,omitempty"`
+	JWSBody  *JWSInfo            `json:"jwsBody,omitempty"`
 }
 
 // OSInfo contains OS-level information
@@ -32,20 +37,77 @@ type OSInfo struct {
 
 // JWTInfo contains decoded JWT information
 type JWTInfo struct {
-	Header  any    `json:"header,omitempty"`
-	Payload any    `json:"payload,omitempty"`
-	Raw     string `json:"raw,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Header            any    `json:"header,omitempty"`
+	Payload           any    `json:"payload,omitempty"`
+	Raw               string `json:"raw,omitempty"`
+	Error             string `json:"error,omitempty"`
+	Valid             bool   `json:"valid"`
+	Algorithm         string `json:"algorithm,omitempty"`
+	KeyID             string `json:"keyId,omitempty"`
+	VerificationError string `json:"verificationError,omitempty"`
 }
 
 var (
 	jwtHeader string
 	logJWT    bool
+
+	jwksStore           *jwks.Store
+	jwtVerifier         *jwks.Verifier
+	jwksURL             string
+	jwksRefreshInterval time.Duration
+	jwtLeeway           time.Duration
+	jwtExpectedAudience string
+	jwtExpectedIssuer   string
 )
 
 func init() {
 	jwtHeader = os.Getenv("JWT_HEADER")
 	logJWT = strings.ToLower(os.Getenv("LOG_JWT")) == "true"
+
+	jwksURL = os.Getenv("JWKS_URL")
+	jwksRefreshInterval = 1 * time.Hour
+	if v := os.Getenv("JWKS_REFRESH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			jwksRefreshInterval = d
+		} else {
+			log.Printf("invalid JWKS_REFRESH_INTERVAL %q, using default: %v", v, err)
+		}
+	}
+
+	jwtLeeway = 0
+	if v := os.Getenv("JWT_LEEWAY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			jwtLeeway = d
+		} else {
+			log.Printf("invalid JWT_LEEWAY %q, using default: %v", v, err)
+		}
+	}
+
+	jwtExpectedAudience = os.Getenv("JWT_EXPECTED_AUDIENCE")
+	jwtExpectedIssuer = os.Getenv("JWT_EXPECTED_ISSUER")
+
+	if jwksURL != "" {
+		jwksStore = jwks.NewStore(jwksURL)
+	}
+
+	var hmacSecret []byte
+	if secret := os.Getenv("JWT_HMAC_SECRET"); secret != "" {
+		hmacSecret = []byte(secret)
+	}
+
+	if jwksStore != nil || hmacSecret != nil {
+		jwtVerifier = jwks.NewVerifier(jwksStore, hmacSecret)
+	}
+}
+
+// startJWKSRefresher begins the background JWKS fetch loop if a JWKS_URL
+// was configured. It is separate from init() so that running tests (which
+// never call main) don't spin up background goroutines or network calls.
+func startJWKSRefresher(stop <-chan struct{}) {
+	if jwksStore == nil {
+		return
+	}
+	jwksStore.StartBackgroundRefresh(jwksRefreshInterval, stop)
 }
 
 func main() {
@@ -54,10 +116,30 @@ func main() {
 		port = "8080"
 	}
 
-	http.HandleFunc("/", loggingMiddleware(echoHandler))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", loggingMiddleware(echoHandler))
+
+	if jwksURL != "" {
+		log.Printf("Refreshing JWKS from %s every %v", jwksURL, jwksRefreshInterval)
+		startJWKSRefresher(nil)
+	}
+
+	if len(echoSinkURIs) > 0 {
+		log.Printf("Mirroring echoed requests to sinks: %v", echoSinkURIs)
+		startSinks()
+	}
+
+	httpHandler := http.Handler(mux)
+	if httpsEnabled {
+		h, err := startHTTPSListener(mux)
+		if err != nil {
+			log.Fatalf("Failed to start HTTPS listener: %v", err)
+		}
+		httpHandler = h
+	}
 
 	log.Printf("Starting HTTP echo server on port %s", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	if err := http.ListenAndServe(":"+port, httpHandler); err != nil {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
@@ -102,12 +184,13 @@ func echoHandler(w http.ResponseWriter, r *http.Request) {
 		ip = strings.Split(forwarded, ",")[0]
 	}
 
-	// Determine protocol
+	// Determine protocol. A direct TLS connection is authoritative; only
+	// fall back to X-Forwarded-Proto for plaintext connections behind a
+	// TLS-terminating proxy.
 	protocol := "http"
 	if r.TLS != nil {
 		protocol = "https"
-	}
-	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+	} else if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
 		protocol = proto
 	}
 
@@ -139,6 +222,13 @@ func echoHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Decode a JOSE/JWS body, e.g. ACME requests
+	if isJOSEContentType(r.Header.Get("Content-Type")) {
+		response.JWSBody = decodeJWS(body)
+	}
+
+	mirrorToSinks(r.Context(), response)
+
 	w.Header().Set("Content-Type", "application/json")
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -147,7 +237,8 @@ func echoHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// decodeJWT decodes a JWT token without verifying the signature
+// decodeJWT decodes a JWT token and, when a verifier is configured via
+// JWKS_URL / JWT_HMAC_SECRET, verifies its signature and standard claims.
 func decodeJWT(token string) *JWTInfo {
 	// Strip "Bearer " prefix if present
 	token = strings.TrimSpace(token)
@@ -179,16 +270,151 @@ func decodeJWT(token string) *JWTInfo {
 		}
 	}
 
-	return &JWTInfo{
+	info := &JWTInfo{
 		Header:  header,
 		Payload: payload,
 	}
+
+	headerMap, _ := header.(map[string]any)
+	alg, _ := headerMap["alg"].(string)
+	kid, _ := headerMap["kid"].(string)
+	info.Algorithm = alg
+	info.KeyID = kid
+
+	sig, err := decodeBase64Segment(parts[2])
+	if err != nil {
+		info.VerificationError = "failed to decode signature: " + err.Error()
+		return info
+	}
+
+	if err := verifyJWTSignature(alg, kid, parts[0]+"."+parts[1], sig); err != nil {
+		info.VerificationError = err.Error()
+		return info
+	}
+
+	payloadMap, _ := payload.(map[string]any)
+	if err := validateStandardClaims(payloadMap); err != nil {
+		info.VerificationError = err.Error()
+		return info
+	}
+
+	info.Valid = true
+	return info
+}
+
+// verifyJWTSignature checks alg/kid/signingInput/sig against the configured
+// verifier. If no verifier is configured (no JWKS_URL and no
+// JWT_HMAC_SECRET), verification is considered unavailable rather than
+// passed.
+func verifyJWTSignature(alg, kid, signingInput string, sig []byte) error {
+	if strings.EqualFold(alg, "none") {
+		return jwks.ErrAlgNone
+	}
+	if jwtVerifier == nil {
+		return errors.New("signature verification not configured")
+	}
+	return jwtVerifier.Verify(alg, kid, []byte(signingInput), sig)
+}
+
+// validateStandardClaims checks exp/nbf/iat (with JWT_LEEWAY) and, when
+// configured, the expected audience and issuer.
+func validateStandardClaims(claims map[string]any) error {
+	now := time.Now()
+
+	if exp, ok := numericClaim(claims, "exp"); ok {
+		if now.After(exp.Add(jwtLeeway)) {
+			return fmt.Errorf("token expired at %s", exp.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if nbf, ok := numericClaim(claims, "nbf"); ok {
+		if now.Before(nbf.Add(-jwtLeeway)) {
+			return fmt.Errorf("token not valid before %s", nbf.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if iat, ok := numericClaim(claims, "iat"); ok {
+		if iat.Add(-jwtLeeway).After(now) {
+			return fmt.Errorf("token issued in the future: %s", iat.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if jwtExpectedAudience != "" {
+		if !claimContains(claims["aud"], jwtExpectedAudience) {
+			return fmt.Errorf("unexpected audience: %v", claims["aud"])
+		}
+	}
+
+	if jwtExpectedIssuer != "" {
+		iss, _ := claims["iss"].(string)
+		if iss != jwtExpectedIssuer {
+			return fmt.Errorf("unexpected issuer: %q", iss)
+		}
+	}
+
+	return nil
+}
+
+// numericClaim reads a Unix-timestamp claim (as decoded from JSON, i.e. a
+// float64) and converts it to a time.Time.
+func numericClaim(claims map[string]any, name string) (time.Time, bool) {
+	v, ok := claims[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(n), 0), true
+}
+
+// claimContains reports whether aud (a string or a []any of strings, per
+// the JWT spec) contains want.
+func claimContains(aud any, want string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == want
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // decodeBase64JSON decodes a base64url-encoded JSON string
 func decodeBase64JSON(s string) (any, error) {
-	// JWT uses base64url encoding (RFC 4648)
-	// Add padding if necessary
+	decoded, err := decodeBase64Segment(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+	if err := json.Unmarshal(decoded, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// splitCSV splits a comma-separated env var value into trimmed, non-empty
+// entries.
+func splitCSV(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// decodeBase64Segment decodes a single base64url-encoded JWT/JWS segment,
+// adding padding as needed since tokens use unpadded base64url (RFC 4648).
+func decodeBase64Segment(s string) ([]byte, error) {
 	switch len(s) % 4 {
 	case 2:
 		s += "=="
@@ -205,10 +431,5 @@ func decodeBase64JSON(s string) (any, error) {
 		}
 	}
 
-	var result any
-	if err := json.Unmarshal(decoded, &result); err != nil {
-		return nil, err
-	}
-
-	return result, nil
+	return decoded, nil
 }
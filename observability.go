@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tamcore/http-https-echo/sinks"
+)
+
+var (
+	echoSinkURIs      []string
+	redactHeaderNames []string
+	redactClaimPaths  []string
+	echoSinkBatchSize int
+	echoSinkFlush     time.Duration
+	echoSinkSecret    []byte
+
+	multiSink *sinks.MultiSink
+)
+
+func init() {
+	echoSinkURIs = splitCSV(os.Getenv("ECHO_SINKS"))
+
+	redactHeaderNames = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+	if v := os.Getenv("REDACT_HEADERS"); v != "" {
+		redactHeaderNames = splitCSV(v)
+	}
+
+	redactClaimPaths = splitCSV(os.Getenv("REDACT_JWT_CLAIMS"))
+
+	echoSinkBatchSize = 10
+	if v := os.Getenv("ECHO_SINK_BATCH_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			echoSinkBatchSize = n
+		} else {
+			log.Printf("invalid ECHO_SINK_BATCH_SIZE %q, using default: %v", v, err)
+		}
+	}
+
+	echoSinkFlush = 5 * time.Second
+	if v := os.Getenv("ECHO_SINK_FLUSH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			echoSinkFlush = d
+		} else {
+			log.Printf("invalid ECHO_SINK_FLUSH_INTERVAL %q, using default: %v", v, err)
+		}
+	}
+
+	if secret := os.Getenv("ECHO_SINK_SECRET"); secret != "" {
+		echoSinkSecret = []byte(secret)
+	}
+}
+
+// startSinks builds the configured transcript sinks. It is separate from
+// init() so that running tests (which never call main) don't open files,
+// dial sockets, or start background goroutines.
+func startSinks() {
+	if len(echoSinkURIs) == 0 {
+		return
+	}
+
+	m, err := sinks.Parse(echoSinkURIs, sinks.Options{
+		BatchSize:     echoSinkBatchSize,
+		FlushInterval: echoSinkFlush,
+		Secret:        echoSinkSecret,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure ECHO_SINKS: %v", err)
+	}
+
+	multiSink = m
+}
+
+// mirrorToSinks redacts response per REDACT_HEADERS/REDACT_JWT_CLAIMS and
+// fans the result out to the configured sinks, without touching response
+// itself (which has already been/will be sent to the client verbatim).
+func mirrorToSinks(ctx context.Context, response EchoResponse) {
+	if multiSink == nil {
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("failed to marshal echo response for sinks: %v", err)
+		return
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		log.Printf("failed to prepare echo response for sinks: %v", err)
+		return
+	}
+
+	sinks.RedactHeaders(record, redactHeaderNames)
+	sinks.RedactClaims(record, redactClaimPaths)
+
+	multiSink.Write(ctx, record)
+}
@@ -0,0 +1,60 @@
+package sinks
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Options configures the sinks built by Parse.
+type Options struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	Secret        []byte
+}
+
+// Parse builds a MultiSink from a list of sink URIs:
+//
+//	stdout://                   - NDJSON lines on stdout
+//	file:///var/log/echo.ndjson - NDJSON lines appended to a file
+//	https://collector/ingest    - batched NDJSON POSTed to a webhook
+//	unix:///tmp/echo.sock       - NDJSON lines written to a Unix socket
+func Parse(uris []string, opts Options) (*MultiSink, error) {
+	m := NewMultiSink()
+
+	for _, raw := range uris {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sink URI %q: %w", raw, err)
+		}
+
+		switch u.Scheme {
+		case "stdout":
+			m.Add(raw, &StdoutSink{})
+
+		case "file":
+			sink, err := NewFileSink(u.Path)
+			if err != nil {
+				return nil, fmt.Errorf("sink %q: %w", raw, err)
+			}
+			m.Add(raw, sink)
+
+		case "http", "https":
+			m.Add(raw, NewWebhookSink(raw, opts.Secret, opts.BatchSize, opts.FlushInterval))
+
+		case "unix":
+			m.Add(raw, NewUnixSink(u.Path))
+
+		default:
+			return nil, fmt.Errorf("unsupported sink scheme %q in %q", u.Scheme, raw)
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,47 @@
+package sinks
+
+import "strings"
+
+// redactedValue replaces any value matched by RedactHeaders or RedactClaims.
+const redactedValue = "***"
+
+// RedactHeaders replaces the values of any header in names (matched
+// case-insensitively) found under record["headers"], in place.
+func RedactHeaders(record map[string]any, names []string) {
+	headers, ok := record["headers"].(map[string]any)
+	if !ok {
+		return
+	}
+	for key := range headers {
+		for _, name := range names {
+			if strings.EqualFold(key, name) {
+				headers[key] = []any{redactedValue}
+			}
+		}
+	}
+}
+
+// RedactClaims replaces the value found by walking record along each
+// dotted path in paths (e.g. "jwt.payload.email"), in place.
+func RedactClaims(record map[string]any, paths []string) {
+	for _, path := range paths {
+		redactPath(record, strings.Split(path, "."))
+	}
+}
+
+func redactPath(node any, segments []string) {
+	m, ok := node.(map[string]any)
+	if !ok || len(segments) == 0 {
+		return
+	}
+
+	key := segments[0]
+	if len(segments) == 1 {
+		if _, exists := m[key]; exists {
+			m[key] = redactedValue
+		}
+		return
+	}
+
+	redactPath(m[key], segments[1:])
+}
@@ -0,0 +1,56 @@
+package sinks
+
+import "testing"
+
+func TestRedactHeaders_CaseInsensitive(t *testing.T) {
+	record := map[string]any{
+		"headers": map[string]any{
+			"authorization": []any{"Bearer secret"},
+			"X-Test":        []any{"keep-me"},
+		},
+	}
+
+	RedactHeaders(record, []string{"Authorization"})
+
+	headers := record["headers"].(map[string]any)
+	if got := headers["authorization"]; got.([]any)[0] != redactedValue {
+		t.Errorf("expected authorization to be redacted, got %v", got)
+	}
+	if got := headers["X-Test"]; got.([]any)[0] != "keep-me" {
+		t.Errorf("expected X-Test to be left untouched, got %v", got)
+	}
+}
+
+func TestRedactClaims_NestedPath(t *testing.T) {
+	record := map[string]any{
+		"jwt": map[string]any{
+			"payload": map[string]any{
+				"email": "user@example.com",
+				"sub":   "123",
+			},
+		},
+	}
+
+	RedactClaims(record, []string{"jwt.payload.email"})
+
+	payload := record["jwt"].(map[string]any)["payload"].(map[string]any)
+	if payload["email"] != redactedValue {
+		t.Errorf("expected jwt.payload.email to be redacted, got %v", payload["email"])
+	}
+	if payload["sub"] != "123" {
+		t.Errorf("expected jwt.payload.sub to be left untouched, got %v", payload["sub"])
+	}
+}
+
+func TestRedactClaims_MissingPathIsNoop(t *testing.T) {
+	record := map[string]any{
+		"jwt": map[string]any{"payload": map[string]any{"sub": "123"}},
+	}
+
+	RedactClaims(record, []string{"jwt.payload.email", "jwt.header.alg"})
+
+	payload := record["jwt"].(map[string]any)["payload"].(map[string]any)
+	if payload["sub"] != "123" {
+		t.Errorf("expected unrelated field to be untouched, got %v", payload["sub"])
+	}
+}
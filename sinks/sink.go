@@ -0,0 +1,79 @@
+// Package sinks mirrors every echoed request to one or more configurable
+// destinations (stdout, a file, a webhook, a Unix domain socket) without
+// letting a slow destination block request handling.
+package sinks
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+)
+
+// Sink writes a single record - typically a JSON-shaped map[string]any -
+// to some destination.
+type Sink interface {
+	Write(ctx context.Context, record any) error
+}
+
+// defaultBufferSize bounds how many pending records a slow sink may queue
+// before new writes are dropped rather than blocking the caller.
+const defaultBufferSize = 256
+
+// MultiSink fans a record out to every registered Sink over a bounded,
+// buffered channel per sink, so one stuck destination (e.g. an
+// unreachable webhook) can't block the others or the request path.
+type MultiSink struct {
+	workers []*sinkWorker
+}
+
+type sinkWorker struct {
+	name    string
+	sink    Sink
+	ch      chan any
+	dropped atomic.Uint64
+}
+
+// NewMultiSink builds an empty MultiSink; call Add for each configured sink.
+func NewMultiSink() *MultiSink {
+	return &MultiSink{}
+}
+
+// Add registers sink under name and starts its delivery goroutine.
+func (m *MultiSink) Add(name string, sink Sink) {
+	w := &sinkWorker{name: name, sink: sink, ch: make(chan any, defaultBufferSize)}
+	m.workers = append(m.workers, w)
+	go w.run()
+}
+
+func (w *sinkWorker) run() {
+	for record := range w.ch {
+		if err := w.sink.Write(context.Background(), record); err != nil {
+			log.Printf("sink %s: write failed: %v", w.name, err)
+		}
+	}
+}
+
+// Write fans record out to every registered sink without blocking. A sink
+// whose buffer is full has the record dropped and its counter incremented.
+func (m *MultiSink) Write(_ context.Context, record any) {
+	for _, w := range m.workers {
+		select {
+		case w.ch <- record:
+		default:
+			if n := w.dropped.Add(1); n == 1 || n%100 == 0 {
+				log.Printf("sink %s: buffer full, dropped %d record(s) so far", w.name, n)
+			}
+		}
+	}
+}
+
+// Dropped returns the number of records dropped so far for the sink
+// registered under name, or 0 if there is no such sink.
+func (m *MultiSink) Dropped(name string) uint64 {
+	for _, w := range m.workers {
+		if w.name == name {
+			return w.dropped.Load()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package sinks
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingSink never returns from Write until release is closed, so tests
+// can fill its worker's buffered channel deterministically.
+type blockingSink struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	written int
+}
+
+func (s *blockingSink) Write(_ context.Context, _ any) error {
+	<-s.release
+	s.mu.Lock()
+	s.written++
+	s.mu.Unlock()
+	return nil
+}
+
+func TestMultiSink_DropsWhenBufferFull(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+
+	m := NewMultiSink()
+	m.Add("blocking", sink)
+
+	// The worker's first Write blocks forever (until released), so every
+	// record after that piles up in the buffered channel.
+	for i := 0; i < defaultBufferSize+10; i++ {
+		m.Write(context.Background(), i)
+	}
+
+	// Give the worker goroutine a moment to drain the channel into its
+	// blocked Write call before we assert on the drop counter.
+	deadline := time.After(time.Second)
+	for m.Dropped("blocking") == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("expected some records to be dropped once the buffer filled up")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	if n := m.Dropped("blocking"); n == 0 {
+		t.Errorf("expected Dropped to report a nonzero count, got %d", n)
+	}
+
+	close(sink.release)
+}
+
+func TestMultiSink_Dropped_UnknownSinkIsZero(t *testing.T) {
+	m := NewMultiSink()
+	if n := m.Dropped("nonexistent"); n != 0 {
+		t.Errorf("expected 0 for an unregistered sink name, got %d", n)
+	}
+}
@@ -0,0 +1,27 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// StdoutSink writes each record as a line of NDJSON to stdout.
+type StdoutSink struct {
+	mu sync.Mutex
+}
+
+// Write implements Sink.
+func (s *StdoutSink) Write(_ context.Context, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
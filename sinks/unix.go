@@ -0,0 +1,50 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// UnixSink writes each record as a line of NDJSON to a Unix domain socket,
+// lazily (re)connecting whenever there is no live connection.
+type UnixSink struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewUnixSink creates a UnixSink that dials addr on first write.
+func NewUnixSink(addr string) *UnixSink {
+	return &UnixSink{addr: addr}
+}
+
+// Write implements Sink.
+func (s *UnixSink) Write(_ context.Context, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.Dial("unix", s.addr)
+		if err != nil {
+			return fmt.Errorf("dial %s: %w", s.addr, err)
+		}
+		s.conn = conn
+	}
+
+	if _, err := s.conn.Write(append(data, '\n')); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("write to %s: %w", s.addr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookSink buffers records and POSTs them as a batch of NDJSON to a
+// URL, signing the batch with HMAC-SHA256 when a secret is configured.
+type WebhookSink struct {
+	url           string
+	secret        []byte
+	batchSize     int
+	flushInterval time.Duration
+	client        *http.Client
+
+	mu    sync.Mutex
+	batch [][]byte
+}
+
+// NewWebhookSink creates a WebhookSink and starts its background flush
+// timer. secret may be nil to disable batch signing.
+func NewWebhookSink(url string, secret []byte, batchSize int, flushInterval time.Duration) *WebhookSink {
+	s := &WebhookSink{
+		url:           url,
+		secret:        secret,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+	go s.flushLoop()
+	return s
+}
+
+// Write implements Sink. It buffers record and flushes immediately once
+// batchSize records have accumulated; otherwise the background timer
+// flushes it.
+func (s *WebhookSink) Write(ctx context.Context, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+
+	s.mu.Lock()
+	s.batch = append(s.batch, data)
+	shouldFlush := len(s.batch) >= s.batchSize
+	s.mu.Unlock()
+
+	if shouldFlush {
+		return s.flush(ctx)
+	}
+	return nil
+}
+
+func (s *WebhookSink) flushLoop() {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		_ = s.flush(context.Background())
+	}
+}
+
+func (s *WebhookSink) flush(ctx context.Context) error {
+	s.mu.Lock()
+	batch := s.batch
+	s.batch = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, line := range batch {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	if len(s.secret) > 0 {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(buf.Bytes())
+		req.Header.Set("X-Echo-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post to %s: %w", s.url, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post to %s: unexpected status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
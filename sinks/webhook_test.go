@@ -0,0 +1,143 @@
+package sinks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type capturedRequest struct {
+	body         []byte
+	contentType  string
+	signatureHdr string
+}
+
+func TestWebhookSink_FlushesOnBatchSize(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		posts []capturedRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		posts = append(posts, capturedRequest{
+			body:         body,
+			contentType:  r.Header.Get("Content-Type"),
+			signatureHdr: r.Header.Get("X-Echo-Signature"),
+		})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	secret := []byte("webhook-secret")
+	sink := NewWebhookSink(server.URL, secret, 2, time.Hour)
+
+	_ = sink.Write(context.Background(), map[string]any{"n": 1})
+	_ = sink.Write(context.Background(), map[string]any{"n": 2})
+
+	deadline := time.After(time.Second)
+	for {
+		mu.Lock()
+		n := len(posts)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected a POST once the batch reached its configured size")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	mu.Lock()
+	got := posts[0]
+	mu.Unlock()
+
+	if got.contentType != "application/x-ndjson" {
+		t.Errorf("expected application/x-ndjson, got %s", got.contentType)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(got.body)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 NDJSON lines in the batch, got %d: %q", len(lines), got.body)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(got.body)
+	wantSig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got.signatureHdr != wantSig {
+		t.Errorf("expected signature %s, got %s", wantSig, got.signatureHdr)
+	}
+}
+
+func TestWebhookSink_FlushesOnInterval(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		posts int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		posts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// batchSize is high enough that only the flush timer can trigger this.
+	sink := NewWebhookSink(server.URL, nil, 100, 20*time.Millisecond)
+	_ = sink.Write(context.Background(), map[string]any{"n": 1})
+
+	deadline := time.After(time.Second)
+	for {
+		mu.Lock()
+		n := posts
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected the flush timer to POST the pending record")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestWebhookSink_NoSecretOmitsSignature(t *testing.T) {
+	var gotSig string
+	sigCh := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		gotSig = r.Header.Get("X-Echo-Signature")
+		close(sigCh)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, nil, 1, time.Hour)
+	_ = sink.Write(context.Background(), map[string]any{"n": 1})
+
+	select {
+	case <-sigCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected a POST for the single buffered record")
+	}
+
+	if gotSig != "" {
+		t.Errorf("expected no signature header without a configured secret, got %q", gotSig)
+	}
+}
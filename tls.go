@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var (
+	httpsEnabled bool
+	httpsPort    string
+	tlsCertFile  string
+	tlsKeyFile   string
+	acmeDomains  []string
+	acmeCacheDir string
+)
+
+func init() {
+	if v, ok := os.LookupEnv("HTTPS_PORT"); ok {
+		httpsPort = v
+	} else {
+		httpsPort = "8443"
+	}
+	httpsEnabled = httpsPort != ""
+
+	tlsCertFile = os.Getenv("TLS_CERT_FILE")
+	tlsKeyFile = os.Getenv("TLS_KEY_FILE")
+
+	acmeDomains = splitCSV(os.Getenv("ACME_DOMAINS"))
+
+	acmeCacheDir = os.Getenv("ACME_CACHE_DIR")
+	if acmeCacheDir == "" {
+		acmeCacheDir = "acme-cache"
+	}
+}
+
+// startHTTPSListener configures and starts a TLS listener on httpsPort
+// serving handler, using (in order of preference) ACME autocert, static
+// cert/key files, or an in-memory self-signed certificate. It returns the
+// handler that HTTP_PORT should serve: plain handler, unless ACME is
+// enabled, in which case the HTTP listener must also answer http-01
+// challenges and redirect everything else to HTTPS.
+func startHTTPSListener(handler http.Handler) (http.Handler, error) {
+	switch {
+	case len(acmeDomains) > 0:
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(acmeDomains...),
+			Cache:      autocert.DirCache(acmeCacheDir),
+		}
+
+		server := &http.Server{
+			Addr:      ":" + httpsPort,
+			Handler:   handler,
+			TLSConfig: m.TLSConfig(),
+		}
+
+		go serveTLS(server, "", "")
+
+		return m.HTTPHandler(nil), nil
+
+	case tlsCertFile != "" && tlsKeyFile != "":
+		server := &http.Server{
+			Addr:    ":" + httpsPort,
+			Handler: handler,
+		}
+
+		go serveTLS(server, tlsCertFile, tlsKeyFile)
+
+		return handler, nil
+
+	default:
+		cert, err := generateSelfSignedCert()
+		if err != nil {
+			return handler, fmt.Errorf("generate self-signed certificate: %w", err)
+		}
+
+		server := &http.Server{
+			Addr:      ":" + httpsPort,
+			Handler:   handler,
+			TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		}
+
+		go serveTLS(server, "", "")
+
+		return handler, nil
+	}
+}
+
+// serveTLS runs the HTTPS listener until it fails or is shut down. It logs
+// rather than exiting the process on failure: this listener is secondary to
+// the HTTP one started in main, and a bind error here (port already taken,
+// permission denied, ...) shouldn't take down a server that's otherwise
+// serving plain HTTP fine.
+func serveTLS(server *http.Server, certFile, keyFile string) {
+	log.Printf("Starting HTTPS echo server on %s", server.Addr)
+	if err := server.ListenAndServeTLS(certFile, keyFile); err != nil {
+		log.Printf("HTTPS server failed: %v", err)
+	}
+}
+
+// generateSelfSignedCert creates an ECDSA P-256 certificate valid for one
+// year, covering localhost and the machine's own hostname, so that the
+// HTTPS listener works out of the box (e.g. `curl -k`) without any
+// configuration.
+func generateSelfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generate key: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	dnsNames := []string{"localhost"}
+	if hostname != "" && hostname != "localhost" {
+		dnsNames = append(dnsNames, hostname)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "http-https-echo"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              dnsNames,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("create certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("marshal key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
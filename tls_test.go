@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSignedCert(t *testing.T) {
+	cert, err := generateSelfSignedCert()
+	if err != nil {
+		t.Fatalf("failed to generate self-signed cert: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+
+	if err := leaf.VerifyHostname("localhost"); err != nil {
+		t.Errorf("expected certificate to cover localhost: %v", err)
+	}
+
+	if time.Until(leaf.NotAfter) < 300*24*time.Hour {
+		t.Errorf("expected roughly a year of validity, got NotAfter=%v", leaf.NotAfter)
+	}
+}
+
+// withTLSGlobals sets the package-level TLS config used by
+// startHTTPSListener for the duration of a test and restores it afterwards,
+// since those are populated once by init() and otherwise shared across tests.
+func withTLSGlobals(t *testing.T, port, certFile, keyFile string, domains []string) {
+	t.Helper()
+
+	origPort, origCert, origKey, origDomains := httpsPort, tlsCertFile, tlsKeyFile, acmeDomains
+	t.Cleanup(func() {
+		httpsPort, tlsCertFile, tlsKeyFile, acmeDomains = origPort, origCert, origKey, origDomains
+	})
+
+	httpsPort, tlsCertFile, tlsKeyFile, acmeDomains = port, certFile, keyFile, domains
+}
+
+func TestStartHTTPSListener_StaticCertReturnsOriginalHandler(t *testing.T) {
+	cert, err := generateSelfSignedCert()
+	if err != nil {
+		t.Fatalf("failed to generate self-signed cert: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certPath, certPEM, 0o644); err != nil {
+		t.Fatalf("failed to write cert file: %v", err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	withTLSGlobals(t, "0", certPath, keyPath, nil)
+
+	mux := http.NewServeMux()
+	handler, err := startHTTPSListener(mux)
+	if err != nil {
+		t.Fatalf("startHTTPSListener returned an error: %v", err)
+	}
+
+	if got, ok := handler.(*http.ServeMux); !ok || got != mux {
+		t.Errorf("expected the static-cert branch to return the original mux unchanged, got %T", handler)
+	}
+}
+
+func TestStartHTTPSListener_ACMERedirectsHTTPToHTTPS(t *testing.T) {
+	withTLSGlobals(t, "0", "", "", []string{"example.com"})
+	acmeCacheDir = t.TempDir()
+
+	mux := http.NewServeMux()
+	handler, err := startHTTPSListener(mux)
+	if err != nil {
+		t.Fatalf("startHTTPSListener returned an error: %v", err)
+	}
+
+	if _, ok := handler.(*http.ServeMux); ok {
+		t.Fatal("expected the ACME branch to wrap the handler in the autocert HTTP-01/redirect handler, not return the raw mux")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/anything", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusFound {
+		t.Errorf("expected a redirect to https for non-challenge paths, got status %d", rr.Code)
+	}
+	if loc := rr.Header().Get("Location"); loc != "https://example.com/anything" {
+		t.Errorf("expected redirect to https://example.com/anything, got %q", loc)
+	}
+}